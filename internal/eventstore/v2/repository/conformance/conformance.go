@@ -0,0 +1,109 @@
+// Package conformance holds a backend-agnostic test suite that every
+// repository.Repository implementation (sql, mongo, ...) runs against a real
+// instance of its backend, so the two stay behaviorally equivalent.
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caos/zitadel/internal/eventstore/v2/repository"
+)
+
+// Run exercises the full Repository interface against repo, which must be
+// backed by an empty, otherwise unused backend instance.
+func Run(t *testing.T, repo repository.Repository) {
+	t.Run("Health", func(t *testing.T) { testHealth(t, repo) })
+	t.Run("Push and Filter", func(t *testing.T) { testPushAndFilter(t, repo) })
+	t.Run("Push enforces unique constraints", func(t *testing.T) { testUniqueConstraints(t, repo) })
+	t.Run("LatestSequence", func(t *testing.T) { testLatestSequence(t, repo) })
+}
+
+func testHealth(t *testing.T, repo repository.Repository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := repo.Health(ctx); err != nil {
+		t.Errorf("Health() unexpected error = %v", err)
+	}
+}
+
+func testPushAndFilter(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	aggregateID := "conformance-push-filter"
+
+	events := []*repository.Event{
+		{AggregateType: "conformance", AggregateID: aggregateID, Type: "conformance.added", Data: []byte(`{}`)},
+		{AggregateType: "conformance", AggregateID: aggregateID, Type: "conformance.changed", Data: []byte(`{}`)},
+	}
+
+	if err := repo.Push(ctx, events); err != nil {
+		t.Fatalf("Push() unexpected error = %v", err)
+	}
+
+	stored, err := repo.Filter(ctx, &repository.SearchQuery{
+		Filters: [][]*repository.Filter{{
+			repository.NewFilter(repository.Field_AggregateID, aggregateID, repository.Operation_Equals),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Filter() unexpected error = %v", err)
+	}
+	if len(stored) != len(events) {
+		t.Fatalf("Filter() returned %d events, want %d", len(stored), len(events))
+	}
+	for i, event := range events {
+		if stored[i].Sequence == 0 {
+			t.Errorf("event %d: Sequence was not assigned by Push()", i)
+		}
+		if stored[i].Type != event.Type {
+			t.Errorf("event %d: Type = %v, want %v", i, stored[i].Type, event.Type)
+		}
+	}
+}
+
+func testUniqueConstraints(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	constraint := &repository.UniqueConstraint{
+		UniqueType:   "conformance_unique",
+		UniqueField:  "only-once",
+		Action:       repository.UniqueConstraintAdd,
+		ErrorMessage: "Errors.Conformance.AlreadyExists",
+	}
+
+	event := &repository.Event{AggregateType: "conformance", AggregateID: "unique-1", Type: "conformance.added", Data: []byte(`{}`)}
+	if err := repo.Push(ctx, []*repository.Event{event}, constraint); err != nil {
+		t.Fatalf("first Push() unexpected error = %v", err)
+	}
+
+	event2 := &repository.Event{AggregateType: "conformance", AggregateID: "unique-2", Type: "conformance.added", Data: []byte(`{}`)}
+	if err := repo.Push(ctx, []*repository.Event{event2}, constraint); err == nil {
+		t.Error("second Push() with the same unique constraint should have failed")
+	}
+}
+
+func testLatestSequence(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	aggregateID := "conformance-latest-sequence"
+
+	events := []*repository.Event{
+		{AggregateType: "conformance", AggregateID: aggregateID, Type: "conformance.added", Data: []byte(`{}`)},
+		{AggregateType: "conformance", AggregateID: aggregateID, Type: "conformance.changed", Data: []byte(`{}`)},
+	}
+	if err := repo.Push(ctx, events); err != nil {
+		t.Fatalf("Push() unexpected error = %v", err)
+	}
+
+	latest, err := repo.LatestSequence(ctx, &repository.SearchQuery{
+		Filters: [][]*repository.Filter{{
+			repository.NewFilter(repository.Field_AggregateID, aggregateID, repository.Operation_Equals),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("LatestSequence() unexpected error = %v", err)
+	}
+	if latest != events[len(events)-1].Sequence {
+		t.Errorf("LatestSequence() = %d, want %d", latest, events[len(events)-1].Sequence)
+	}
+}
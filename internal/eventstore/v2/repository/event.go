@@ -0,0 +1,56 @@
+package repository
+
+import "time"
+
+// AggregateType is the object name
+type AggregateType string
+
+// EventType is the description of the change
+type EventType string
+
+// Version is the semver version of an event
+type Version string
+
+// Event is a stored or to be stored event
+type Event struct {
+	// ID is the unique identifier of the event
+	ID string
+	// Sequence is the unique and monotonically increasing sequence of the event within the eventstore
+	Sequence uint64
+	// PreviousAggregateSequence is the sequence of the previous event of the aggregate at the time this event was created
+	PreviousAggregateSequence uint64
+	// CreationDate is the time the event was created
+	CreationDate time.Time
+	// Type is the description of the change
+	Type EventType
+	// Data is the payload of the event. It is stored as json.
+	Data []byte
+	// EditorService is the service which created the event
+	EditorService string
+	// EditorUser is the user which created the event
+	EditorUser string
+	// Version is the semver version of this event
+	Version Version
+	// AggregateID is the id of the aggregate the event belongs to
+	AggregateID string
+	// AggregateType is the object name of the aggregate the event belongs to
+	AggregateType AggregateType
+	// ResourceOwner is the organization the aggregate belongs to
+	ResourceOwner string
+}
+
+// UniqueConstraintAction defines what should happen to a unique constraint on push
+type UniqueConstraintAction int32
+
+const (
+	UniqueConstraintAdd UniqueConstraintAction = iota
+	UniqueConstraintRemoved
+)
+
+// UniqueConstraint guarantees, that a field of an aggregate is unique in the eventstore
+type UniqueConstraint struct {
+	UniqueType   string
+	UniqueField  string
+	Action       UniqueConstraintAction
+	ErrorMessage string
+}
@@ -0,0 +1,48 @@
+package repository
+
+// Field is a column of the events table a Filter can be applied on
+type Field int32
+
+const (
+	Field_AggregateID Field = iota
+	Field_AggregateType
+	Field_LatestSequence
+	Field_ResourceOwner
+	Field_EditorService
+	Field_EditorUser
+	Field_EventType
+)
+
+// Operation defines how a Filter's Value is compared against a Field
+type Operation int32
+
+const (
+	Operation_Equals Operation = iota
+	Operation_Greater
+	Operation_Less
+	Operation_In
+)
+
+// Filter narrows down a search for events on a single Field
+type Filter struct {
+	Field     Field
+	Value     interface{}
+	Operation Operation
+}
+
+// NewFilter creates a Filter which can be used in a SearchQuery
+func NewFilter(field Field, value interface{}, operation Operation) *Filter {
+	return &Filter{
+		Field:     field,
+		Value:     value,
+		Operation: operation,
+	}
+}
+
+// SearchQuery describes which events should be returned by a Repository's Filter
+type SearchQuery struct {
+	Columns Field
+	Limit   uint64
+	Desc    bool
+	Filters [][]*Filter
+}
@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/caos/zitadel/internal/eventstore/v2/repository"
+)
+
+// queryToFilter translates a repository.SearchQuery into the bson.M filter
+// and find options passed to the events collection. Each group of Filters
+// in searchQuery.Filters is AND-ed, the groups themselves are OR-ed.
+func queryToFilter(searchQuery *repository.SearchQuery) (bson.M, *options.FindOptions) {
+	opts := options.Find().SetSort(bson.D{{Key: "event_sequence", Value: 1}})
+	if searchQuery == nil {
+		return bson.M{}, opts
+	}
+	if searchQuery.Limit > 0 {
+		opts.SetLimit(int64(searchQuery.Limit))
+	}
+	if searchQuery.Desc {
+		opts.SetSort(bson.D{{Key: "event_sequence", Value: -1}})
+	}
+
+	if len(searchQuery.Filters) == 0 {
+		return bson.M{}, opts
+	}
+
+	or := make(bson.A, 0, len(searchQuery.Filters))
+	for _, group := range searchQuery.Filters {
+		and := make(bson.A, 0, len(group))
+		for _, f := range group {
+			and = append(and, bson.M{fieldName(f.Field): condition(f)})
+		}
+		if len(and) > 0 {
+			or = append(or, bson.M{"$and": and})
+		}
+	}
+	if len(or) == 0 {
+		return bson.M{}, opts
+	}
+	if len(or) == 1 {
+		return or[0].(bson.M), opts
+	}
+	return bson.M{"$or": or}, opts
+}
+
+// condition translates a single repository.Filter into the bson operator
+// matching its Operation.
+func condition(f *repository.Filter) bson.M {
+	switch f.Operation {
+	case repository.Operation_Greater:
+		return bson.M{"$gt": f.Value}
+	case repository.Operation_Less:
+		return bson.M{"$lt": f.Value}
+	case repository.Operation_In:
+		return bson.M{"$in": f.Value}
+	case repository.Operation_Equals:
+		fallthrough
+	default:
+		return bson.M{"$eq": f.Value}
+	}
+}
+
+// fieldName maps a repository.Field to its document field in the events
+// collection.
+func fieldName(field repository.Field) string {
+	switch field {
+	case repository.Field_AggregateID:
+		return "aggregate_id"
+	case repository.Field_AggregateType:
+		return "aggregate_type"
+	case repository.Field_EditorService:
+		return "editor_service"
+	case repository.Field_EditorUser:
+		return "editor_user"
+	case repository.Field_EventType:
+		return "event_type"
+	case repository.Field_LatestSequence:
+		return "event_sequence"
+	case repository.Field_ResourceOwner:
+		return "resource_owner"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,236 @@
+// Package mongo implements the repository.Repository interface on top of
+// MongoDB as an alternative backend to CockroachDB.
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/caos/zitadel/internal/errors"
+	"github.com/caos/zitadel/internal/eventstore/v2/repository"
+)
+
+const (
+	eventsCollection            = "events"
+	countersCollection          = "counters"
+	uniqueConstraintsCollection = "unique_constraints"
+
+	sequenceCounterID = "event_sequence"
+)
+
+var _ repository.Repository = (*Mongo)(nil)
+
+// Mongo is the MongoDB implementation of the eventstore repository
+type Mongo struct {
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+// NewMongo creates a Mongo repository on top of the given client and
+// database name, and ensures the indexes required to enforce uniqueness are
+// present.
+func NewMongo(ctx context.Context, client *mongo.Client, database string) (*Mongo, error) {
+	m := &Mongo{
+		client: client,
+		db:     client.Database(database),
+	}
+	if err := m.ensureIndexes(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Mongo) ensureIndexes(ctx context.Context) error {
+	_, err := m.db.Collection(uniqueConstraintsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "type", Value: 1}, {Key: "field", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return errors.ThrowInternal(err, "MONGO-ShVa9", "unable to create unique constraint index")
+	}
+	return nil
+}
+
+// event is the document representation of a repository.Event
+type event struct {
+	ID                        string    `bson:"_id"`
+	Sequence                  uint64    `bson:"event_sequence"`
+	PreviousAggregateSequence uint64    `bson:"previous_aggregate_sequence"`
+	CreationDate              int64     `bson:"creation_date"`
+	Type                      string    `bson:"event_type"`
+	Data                      []byte    `bson:"event_data"`
+	EditorService             string    `bson:"editor_service"`
+	EditorUser                string    `bson:"editor_user"`
+	Version                   string    `bson:"version"`
+	AggregateID               string    `bson:"aggregate_id"`
+	AggregateType             string    `bson:"aggregate_type"`
+	ResourceOwner             string    `bson:"resource_owner"`
+}
+
+// uniqueConstraint is the document representation of a
+// repository.UniqueConstraint
+type uniqueConstraint struct {
+	Type  string `bson:"type"`
+	Field string `bson:"field"`
+}
+
+// Push stores events inside a session transaction so that every event of a
+// Push call is appended atomically, assigns each event a sequence from the
+// counters collection, a creation date and (if it has none) an ID, and
+// enforces the given unique constraints.
+func (m *Mongo) Push(ctx context.Context, events []*repository.Event, uniqueConstraints ...*repository.UniqueConstraint) error {
+	session, err := m.client.StartSession()
+	if err != nil {
+		return errors.ThrowInternal(err, "MONGO-aAe4f", "unable to start session")
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := m.handleUniqueConstraints(sessCtx, uniqueConstraints...); err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			seq, err := m.nextSequence(sessCtx)
+			if err != nil {
+				return nil, err
+			}
+			e.Sequence = seq
+			if e.ID == "" {
+				e.ID = primitive.NewObjectID().Hex()
+			}
+			e.CreationDate = time.Now()
+			doc := eventToDoc(e)
+			if _, err := m.db.Collection(eventsCollection).InsertOne(sessCtx, doc); err != nil {
+				return nil, errors.ThrowInternal(err, "MONGO-1bk61", "unable to store event")
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		if errors.IsErrorAlreadyExists(err) {
+			return err
+		}
+		return errors.ThrowInternal(err, "MONGO-Dl8ein", "push transaction failed")
+	}
+	return nil
+}
+
+// nextSequence atomically increments and returns the global event sequence
+// counter using findAndModify($inc).
+func (m *Mongo) nextSequence(ctx context.Context) (uint64, error) {
+	res := m.db.Collection(countersCollection).FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": sequenceCounterID},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().
+			SetUpsert(true).
+			SetReturnDocument(options.After),
+	)
+	var counter struct {
+		Seq uint64 `bson:"seq"`
+	}
+	if err := res.Decode(&counter); err != nil {
+		return 0, errors.ThrowInternal(err, "MONGO-Oe0rt", "unable to increment sequence counter")
+	}
+	return counter.Seq, nil
+}
+
+func (m *Mongo) handleUniqueConstraints(ctx context.Context, constraints ...*repository.UniqueConstraint) error {
+	coll := m.db.Collection(uniqueConstraintsCollection)
+	for _, constraint := range constraints {
+		var err error
+		switch constraint.Action {
+		case repository.UniqueConstraintAdd:
+			_, err = coll.InsertOne(ctx, uniqueConstraint{Type: constraint.UniqueType, Field: constraint.UniqueField})
+		case repository.UniqueConstraintRemoved:
+			_, err = coll.DeleteOne(ctx, bson.M{"type": constraint.UniqueType, "field": constraint.UniqueField})
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.ThrowAlreadyExists(err, "MONGO-pTiUq", constraint.ErrorMessage)
+		}
+		if err != nil {
+			return errors.ThrowInternal(err, "MONGO-iSh1U", "unable to apply unique constraint")
+		}
+	}
+	return nil
+}
+
+// Filter returns all events matching searchQuery
+func (m *Mongo) Filter(ctx context.Context, searchQuery *repository.SearchQuery) ([]*repository.Event, error) {
+	filter, findOpts := queryToFilter(searchQuery)
+	cursor, err := m.db.Collection(eventsCollection).Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "MONGO-Cr63x", "unable to filter events")
+	}
+	defer cursor.Close(ctx)
+
+	events := make([]*repository.Event, 0)
+	for cursor.Next(ctx) {
+		var doc event
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, errors.ThrowInternal(err, "MONGO-Bg91a", "unable to decode event")
+		}
+		events = append(events, docToEvent(&doc))
+	}
+	return events, nil
+}
+
+// LatestSequence returns the latest sequence matching searchQuery
+func (m *Mongo) LatestSequence(ctx context.Context, searchQuery *repository.SearchQuery) (uint64, error) {
+	filter, _ := queryToFilter(searchQuery)
+	opts := options.FindOne().SetSort(bson.D{{Key: "event_sequence", Value: -1}})
+
+	var doc event
+	err := m.db.Collection(eventsCollection).FindOne(ctx, filter, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.ThrowInternal(err, "MONGO-Vue0r", "unable to query latest sequence")
+	}
+	return doc.Sequence, nil
+}
+
+// Health checks whether the connection to MongoDB is up
+func (m *Mongo) Health(ctx context.Context) error {
+	return m.client.Ping(ctx, nil)
+}
+
+func eventToDoc(e *repository.Event) event {
+	return event{
+		ID:                        e.ID,
+		Sequence:                  e.Sequence,
+		PreviousAggregateSequence: e.PreviousAggregateSequence,
+		CreationDate:              e.CreationDate.UnixNano(),
+		Type:                      string(e.Type),
+		Data:                      e.Data,
+		EditorService:             e.EditorService,
+		EditorUser:                e.EditorUser,
+		Version:                   string(e.Version),
+		AggregateID:               e.AggregateID,
+		AggregateType:             string(e.AggregateType),
+		ResourceOwner:             e.ResourceOwner,
+	}
+}
+
+func docToEvent(d *event) *repository.Event {
+	return &repository.Event{
+		ID:                        d.ID,
+		Sequence:                  d.Sequence,
+		PreviousAggregateSequence: d.PreviousAggregateSequence,
+		CreationDate:              time.Unix(0, d.CreationDate),
+		Type:                      repository.EventType(d.Type),
+		Data:                      d.Data,
+		EditorService:             d.EditorService,
+		EditorUser:                d.EditorUser,
+		Version:                   repository.Version(d.Version),
+		AggregateID:               d.AggregateID,
+		AggregateType:             repository.AggregateType(d.AggregateType),
+		ResourceOwner:             d.ResourceOwner,
+	}
+}
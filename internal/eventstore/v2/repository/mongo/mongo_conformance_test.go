@@ -0,0 +1,35 @@
+//go:build integration
+
+package mongo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/caos/zitadel/internal/eventstore/v2/repository/conformance"
+)
+
+func TestMongo_Conformance(t *testing.T) {
+	uri := os.Getenv("ZITADEL_TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("ZITADEL_TEST_MONGO_URI not set")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("unable to connect to mongo: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	repo, err := NewMongo(ctx, client, "zitadel_conformance")
+	if err != nil {
+		t.Fatalf("unable to create mongo repository: %v", err)
+	}
+
+	conformance.Run(t, repo)
+}
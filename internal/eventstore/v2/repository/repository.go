@@ -0,0 +1,19 @@
+package repository
+
+import "context"
+
+// Repository abstracts the storage backend an eventstore is run on top of.
+// Implementations live in their own subpackage (sql, mongo, ...) and keep
+// backend-specific helpers such as placeholder formatting or query
+// translation internal to that subpackage.
+type Repository interface {
+	// Push stores the given events, enforcing uniqueConstraints, and assigns
+	// each event its sequence and creation date.
+	Push(ctx context.Context, events []*Event, uniqueConstraints ...*UniqueConstraint) error
+	// Filter returns all events matching searchQuery
+	Filter(ctx context.Context, searchQuery *SearchQuery) ([]*Event, error)
+	// LatestSequence returns the latest sequence matching searchQuery
+	LatestSequence(ctx context.Context, searchQuery *SearchQuery) (uint64, error)
+	// Health checks whether the backend is reachable
+	Health(ctx context.Context) error
+}
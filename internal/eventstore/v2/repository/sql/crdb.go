@@ -0,0 +1,265 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/caos/logging"
+
+	"github.com/caos/zitadel/internal/errors"
+	"github.com/caos/zitadel/internal/eventstore/v2/repository"
+	"github.com/caos/zitadel/internal/eventstore/v2/stream"
+)
+
+var _ repository.Repository = (*CRDB)(nil)
+
+// CRDB is the CockroachDB implementation of the eventstore repository
+type CRDB struct {
+	client    *sql.DB
+	publisher *stream.Publisher
+}
+
+// NewCRDB creates a CRDB repository using client to access the database.
+// Events pushed through the returned repository are additionally handed to
+// publisher, if one is set via SetPublisher.
+func NewCRDB(client *sql.DB) *CRDB {
+	return &CRDB{client: client}
+}
+
+// SetPublisher attaches a stream.Publisher which is notified about every
+// event committed through Push. A nil publisher disables notification.
+func (db *CRDB) SetPublisher(publisher *stream.Publisher) {
+	db.publisher = publisher
+}
+
+// Push inserts events into the eventstore.events table, enforces the given
+// unique constraints and, only once the transaction has actually committed,
+// hands the events to the configured stream.Publisher so subscribers don't
+// have to poll the table. Events are never published if the commit fails,
+// so subscribers never see events that weren't durably stored.
+func (db *CRDB) Push(ctx context.Context, events []*repository.Event, uniqueConstraints ...*repository.UniqueConstraint) error {
+	tx, err := db.client.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.ThrowInternal(err, "SQL-SBP37", "unable to begin transaction")
+	}
+
+	if err := db.handleUniqueConstraints(tx, uniqueConstraints...); err != nil {
+		logRollbackError(tx.Rollback())
+		return err
+	}
+
+	if err := db.insertEvents(tx, events); err != nil {
+		logRollbackError(tx.Rollback())
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.ThrowInternal(err, "SQL-ahu01", "unable to commit transaction")
+	}
+
+	if db.publisher != nil {
+		db.publisher.Publish(events...)
+	}
+
+	return nil
+}
+
+func (db *CRDB) insertEvents(tx *sql.Tx, events []*repository.Event) error {
+	for _, event := range events {
+		err := tx.QueryRow(insertEventStmt,
+			event.AggregateType,
+			event.AggregateID,
+			event.EditorUser,
+			event.EditorService,
+			event.Type,
+			event.Data,
+			event.ResourceOwner,
+			event.PreviousAggregateSequence,
+		).Scan(&event.ID, &event.Sequence, &event.CreationDate)
+		if err != nil {
+			return errors.ThrowInternal(err, "SQL-IP3de", "unable to store event")
+		}
+	}
+	return nil
+}
+
+func (db *CRDB) handleUniqueConstraints(tx *sql.Tx, constraints ...*repository.UniqueConstraint) error {
+	for _, constraint := range constraints {
+		var err error
+		switch constraint.Action {
+		case repository.UniqueConstraintAdd:
+			_, err = tx.Exec(insertUniqueConstraintStmt, constraint.UniqueType, constraint.UniqueField)
+		case repository.UniqueConstraintRemoved:
+			_, err = tx.Exec(deleteUniqueConstraintStmt, constraint.UniqueType, constraint.UniqueField)
+		}
+		if err != nil {
+			return errors.ThrowAlreadyExists(err, "SQL-M0dsf", constraint.ErrorMessage)
+		}
+	}
+	return nil
+}
+
+// Filter returns all events matching the given search query
+func (db *CRDB) Filter(ctx context.Context, searchQuery *repository.SearchQuery) ([]*repository.Event, error) {
+	where, args := db.buildWhere(searchQuery)
+	query := selectEventsStmt + where + " ORDER BY event_sequence"
+	if searchQuery != nil && searchQuery.Desc {
+		query += " DESC"
+	}
+	if searchQuery != nil && searchQuery.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", searchQuery.Limit)
+	}
+	query = db.placeholder(query)
+
+	rows, err := db.client.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.ThrowInternal(err, "SQL-dn9os", "unable to filter events")
+	}
+	defer rows.Close()
+
+	events := make([]*repository.Event, 0)
+	for rows.Next() {
+		event := new(repository.Event)
+		if err := rows.Scan(
+			&event.ID,
+			&event.Sequence,
+			&event.PreviousAggregateSequence,
+			&event.CreationDate,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.EditorUser,
+			&event.EditorService,
+			&event.Type,
+			&event.Data,
+			&event.ResourceOwner,
+		); err != nil {
+			return nil, errors.ThrowInternal(err, "SQL-6QJvo", "unable to scan event")
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ThrowInternal(err, "SQL-Bl9gT", "error while iterating filtered events")
+	}
+	return events, nil
+}
+
+// LatestSequence returns the latest sequence found by the given search query
+func (db *CRDB) LatestSequence(ctx context.Context, searchQuery *repository.SearchQuery) (uint64, error) {
+	where, args := db.buildWhere(searchQuery)
+	query := db.placeholder("SELECT COALESCE(MAX(event_sequence), 0) FROM eventstore.events" + where)
+
+	var sequence uint64
+	if err := db.client.QueryRowContext(ctx, query, args...).Scan(&sequence); err != nil {
+		return 0, errors.ThrowInternal(err, "SQL-aJ1sk", "unable to query latest sequence")
+	}
+	return sequence, nil
+}
+
+// buildWhere renders searchQuery.Filters into a " WHERE ..." clause (or ""
+// if there are none) together with its positional arguments. Each group of
+// repository.Filter is AND-ed, the groups themselves are OR-ed, reusing the
+// conditionFormat/operation/columnName helpers already covered by the CRDB
+// unit tests.
+func (db *CRDB) buildWhere(searchQuery *repository.SearchQuery) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	if searchQuery == nil || len(searchQuery.Filters) == 0 {
+		return "", args
+	}
+
+	orClauses := make([]string, 0, len(searchQuery.Filters))
+	for _, group := range searchQuery.Filters {
+		andClauses := make([]string, 0, len(group))
+		for _, f := range group {
+			format := db.conditionFormat(f.Operation)
+			andClauses = append(andClauses, fmt.Sprintf(format, db.columnName(f.Field), db.operation(f.Operation)))
+			args = append(args, f.Value)
+		}
+		if len(andClauses) > 0 {
+			orClauses = append(orClauses, "("+strings.Join(andClauses, " AND ")+")")
+		}
+	}
+	if len(orClauses) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(orClauses, " OR "), args
+}
+
+// Health checks whether the connection to CockroachDB is up
+func (db *CRDB) Health(ctx context.Context) error {
+	return db.client.PingContext(ctx)
+}
+
+// placeholder replaces the `?` placeholders of query with CockroachDB's
+// positional `$1`, `$2`, ... placeholders.
+func (db *CRDB) placeholder(query string) string {
+	index := 1
+	for strings.ContainsRune(query, '?') {
+		query = strings.Replace(query, "?", "$"+strconv.Itoa(index), 1)
+		index++
+	}
+	return query
+}
+
+// operation maps a repository.Operation to its SQL operator
+func (db *CRDB) operation(operation repository.Operation) string {
+	switch operation {
+	case repository.Operation_Equals, repository.Operation_In:
+		return "="
+	case repository.Operation_Greater:
+		return ">"
+	case repository.Operation_Less:
+		return "<"
+	default:
+		return ""
+	}
+}
+
+// conditionFormat returns the format string used to render a single filter
+// condition for the given operation
+func (db *CRDB) conditionFormat(operation repository.Operation) string {
+	if operation == repository.Operation_In {
+		return "%s %s ANY(?)"
+	}
+	return "%s %s ?"
+}
+
+// columnName maps a repository.Field to its column in eventstore.events
+func (db *CRDB) columnName(field repository.Field) string {
+	switch field {
+	case repository.Field_AggregateID:
+		return "aggregate_id"
+	case repository.Field_AggregateType:
+		return "aggregate_type"
+	case repository.Field_EditorService:
+		return "editor_service"
+	case repository.Field_EditorUser:
+		return "editor_user"
+	case repository.Field_EventType:
+		return "event_type"
+	case repository.Field_LatestSequence:
+		return "event_sequence"
+	case repository.Field_ResourceOwner:
+		return "resource_owner"
+	default:
+		return ""
+	}
+}
+
+func logRollbackError(err error) {
+	logging.Log("SQL-6WsXO").OnError(err).Warn("unable to rollback tx")
+}
+
+const (
+	insertEventStmt = "INSERT INTO eventstore.events " +
+		"(aggregate_type, aggregate_id, editor_user, editor_service, event_type, event_data, resource_owner, previous_aggregate_sequence) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) " +
+		"RETURNING id, event_sequence, creation_date"
+	selectEventsStmt = "SELECT id, event_sequence, previous_aggregate_sequence, creation_date, " +
+		"aggregate_type, aggregate_id, editor_user, editor_service, event_type, event_data, resource_owner " +
+		"FROM eventstore.events"
+	insertUniqueConstraintStmt = "INSERT INTO eventstore.unique_constraints (unique_type, unique_field) VALUES ($1, $2)"
+	deleteUniqueConstraintStmt = "DELETE FROM eventstore.unique_constraints WHERE unique_type = $1 AND unique_field = $2"
+)
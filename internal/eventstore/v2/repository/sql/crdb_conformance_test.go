@@ -0,0 +1,25 @@
+//go:build integration
+
+package sql
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/caos/zitadel/internal/eventstore/v2/repository/conformance"
+)
+
+func TestCRDB_Conformance(t *testing.T) {
+	dsn := os.Getenv("ZITADEL_TEST_CRDB_DSN")
+	if dsn == "" {
+		t.Skip("ZITADEL_TEST_CRDB_DSN not set")
+	}
+	client, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("unable to open CRDB connection: %v", err)
+	}
+	defer client.Close()
+
+	conformance.Run(t, NewCRDB(client))
+}
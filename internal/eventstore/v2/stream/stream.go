@@ -0,0 +1,294 @@
+// Package stream provides an in-process publish/subscribe API on top of the
+// events committed through an eventstore/v2 repository. It lets projections
+// and the notification handler react to newly pushed events without polling
+// the eventstore.events table.
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/caos/zitadel/internal/eventstore/v2/repository"
+)
+
+// ErrSubscriptionClosed is returned by Subscription.Next once the Publisher
+// was stopped or the subscriber fell behind past the retained window.
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+// Filter narrows down which events a Subscription is notified about. A zero
+// value field is not taken into account.
+type Filter struct {
+	AggregateType repository.AggregateType
+	AggregateID   string
+	EventType     repository.EventType
+	MinSequence   uint64
+}
+
+func (f *Filter) matches(event *repository.Event) bool {
+	if f == nil {
+		return true
+	}
+	if f.AggregateType != "" && f.AggregateType != event.AggregateType {
+		return false
+	}
+	if f.AggregateID != "" && f.AggregateID != event.AggregateID {
+		return false
+	}
+	if f.EventType != "" && f.EventType != event.Type {
+		return false
+	}
+	if event.Sequence < f.MinSequence {
+		return false
+	}
+	return true
+}
+
+// bufferItem is a node of the lock-free append-only ring buffer. Producers
+// append a new item by CAS-ing it onto the current tail's next pointer;
+// subscribers walk next to catch up with events they haven't seen yet.
+type bufferItem struct {
+	events  []*repository.Event
+	created time.Time
+	next    atomic.Pointer[bufferItem]
+}
+
+// Config configures the retention of the ring buffer maintained by a
+// Publisher.
+type Config struct {
+	// TTL is the maximum age of a bufferItem before the reaper prunes it.
+	TTL time.Duration
+	// MaxItems caps the number of items kept in the buffer regardless of TTL.
+	MaxItems int
+	// ReapInterval is how often the background reaper runs.
+	ReapInterval time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.TTL <= 0 {
+		c.TTL = 5 * time.Minute
+	}
+	if c.MaxItems <= 0 {
+		c.MaxItems = 10_000
+	}
+	if c.ReapInterval <= 0 {
+		c.ReapInterval = 30 * time.Second
+	}
+	return c
+}
+
+// Publisher hands every event pushed through a repository to the
+// subscribers currently registered, retaining recent events in a bounded
+// ring buffer so a Subscription can catch up to the current tail.
+type Publisher struct {
+	cfg Config
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	head *bufferItem
+	tail atomic.Pointer[bufferItem]
+	len  int
+
+	closed   bool
+	stopReap chan struct{}
+}
+
+// NewPublisher creates a Publisher and starts its background reaper.
+func NewPublisher(cfg Config) *Publisher {
+	cfg = cfg.withDefaults()
+	root := &bufferItem{created: timeNow()}
+	p := &Publisher{
+		cfg:      cfg,
+		head:     root,
+		stopReap: make(chan struct{}),
+	}
+	p.tail.Store(root)
+	p.cond = sync.NewCond(&p.mu)
+	go p.reap()
+	return p
+}
+
+// timeNow returns the current time; NewPublisher uses it to stamp the
+// zero-event root item with a creation time.
+func timeNow() time.Time { return time.Now() }
+
+// Publish appends events as a new bufferItem with a single CAS on the
+// current tail's next pointer and wakes up any subscriber blocked in Next.
+// It is safe to call concurrently with Subscribe, Next and other Publish
+// calls.
+func (p *Publisher) Publish(events ...*repository.Event) {
+	if len(events) == 0 || p.isClosed() {
+		return
+	}
+	item := &bufferItem{events: events, created: time.Now()}
+
+	for {
+		tail := p.tail.Load()
+		if tail.next.CompareAndSwap(nil, item) {
+			p.tail.CompareAndSwap(tail, item)
+			break
+		}
+		// another producer already linked a new tail, help it along and retry
+		p.tail.CompareAndSwap(tail, tail.next.Load())
+	}
+
+	p.mu.Lock()
+	p.len++
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
+
+func (p *Publisher) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// Subscribe registers a new Subscription starting at the current tail of
+// the buffer. filter may be nil to receive every event.
+func (p *Publisher) Subscribe(filter *Filter) *Subscription {
+	p.mu.Lock()
+	cur := p.tail.Load()
+	p.mu.Unlock()
+
+	return &Subscription{
+		publisher: p,
+		filter:    filter,
+		cur:       cur,
+	}
+}
+
+// Stop closes the Publisher. All subscribers blocked in or future callers
+// of Next receive ErrSubscriptionClosed, and the background reaper exits.
+func (p *Publisher) Stop() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.stopReap)
+	p.cond.Broadcast()
+}
+
+// reap periodically prunes items older than the configured TTL and enforces
+// MaxItems by advancing head past the oldest retained item.
+func (p *Publisher) reap() {
+	ticker := time.NewTicker(p.cfg.ReapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopReap:
+			return
+		case <-ticker.C:
+			p.pruneLocked()
+		}
+	}
+}
+
+func (p *Publisher) pruneLocked() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	deadline := time.Now().Add(-p.cfg.TTL)
+	for p.head.next.Load() != nil && p.len > p.cfg.MaxItems {
+		p.head = p.head.next.Load()
+		p.len--
+	}
+	for p.head.next.Load() != nil && p.head.next.Load().created.Before(deadline) {
+		p.head = p.head.next.Load()
+		p.len--
+	}
+}
+
+// Subscription is a cursor into a Publisher's ring buffer, returned by
+// Publisher.Subscribe.
+type Subscription struct {
+	publisher *Publisher
+	filter    *Filter
+	cur       *bufferItem
+	pending   []*repository.Event
+}
+
+// Next blocks until the next event matching the Subscription's filter is
+// available, the context is done, or the Publisher is stopped. It returns
+// ErrSubscriptionClosed once the subscriber has fallen behind past the
+// retained window of the ring buffer.
+func (s *Subscription) Next(ctx context.Context) (*repository.Event, error) {
+	for {
+		if len(s.pending) > 0 {
+			event := s.pending[0]
+			s.pending = s.pending[1:]
+			if s.filter.matches(event) {
+				return event, nil
+			}
+			continue
+		}
+
+		next, err := s.advance(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.cur = next
+		s.pending = next.events
+	}
+}
+
+// advance waits for the cursor's next item to appear and returns it.
+func (s *Subscription) advance(ctx context.Context) (*bufferItem, error) {
+	p := s.publisher
+
+	p.mu.Lock()
+	for {
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrSubscriptionClosed
+		}
+		if s.publisherFellBehindLocked() {
+			p.mu.Unlock()
+			return nil, ErrSubscriptionClosed
+		}
+		if next := s.cur.next.Load(); next != nil {
+			p.mu.Unlock()
+			return next, nil
+		}
+
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				p.mu.Lock()
+				p.cond.Broadcast()
+				p.mu.Unlock()
+			case <-done:
+			}
+		}()
+		p.cond.Wait()
+		close(done)
+
+		if err := ctx.Err(); err != nil {
+			p.mu.Unlock()
+			return nil, err
+		}
+	}
+}
+
+// publisherFellBehindLocked reports whether the cursor's item was pruned
+// from the buffer by the reaper before the subscriber could read it. Must
+// be called with p.mu held.
+func (s *Subscription) publisherFellBehindLocked() bool {
+	p := s.publisher
+	if s.cur == p.head {
+		return false
+	}
+	for item := p.head; item != nil; item = item.next.Load() {
+		if item == s.cur {
+			return false
+		}
+	}
+	return true
+}
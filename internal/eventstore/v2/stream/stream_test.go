@@ -0,0 +1,80 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caos/zitadel/internal/eventstore/v2/repository"
+)
+
+func TestPublisher_PublishAndNext(t *testing.T) {
+	p := NewPublisher(Config{})
+	defer p.Stop()
+
+	sub := p.Subscribe(nil)
+
+	event := &repository.Event{AggregateType: "user", AggregateID: "1", Type: "user.added", Sequence: 1}
+	p.Publish(event)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := sub.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next() unexpected error = %v", err)
+	}
+	if got != event {
+		t.Errorf("Next() = %v, want %v", got, event)
+	}
+}
+
+func TestSubscription_Filter(t *testing.T) {
+	p := NewPublisher(Config{})
+	defer p.Stop()
+
+	sub := p.Subscribe(&Filter{AggregateType: "user"})
+
+	other := &repository.Event{AggregateType: "org", AggregateID: "1", Type: "org.added", Sequence: 1}
+	match := &repository.Event{AggregateType: "user", AggregateID: "1", Type: "user.added", Sequence: 2}
+	p.Publish(other, match)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := sub.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next() unexpected error = %v", err)
+	}
+	if got != match {
+		t.Errorf("Next() = %v, want the filtered match %v", got, match)
+	}
+}
+
+func TestSubscription_Next_ClosedPublisher(t *testing.T) {
+	p := NewPublisher(Config{})
+	sub := p.Subscribe(nil)
+	p.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := sub.Next(ctx)
+	if err != ErrSubscriptionClosed {
+		t.Errorf("Next() error = %v, want %v", err, ErrSubscriptionClosed)
+	}
+}
+
+func TestSubscription_Next_ContextCanceled(t *testing.T) {
+	p := NewPublisher(Config{})
+	defer p.Stop()
+	sub := p.Subscribe(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := sub.Next(ctx)
+	if err != context.Canceled {
+		t.Errorf("Next() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,74 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/caos/zitadel/internal/errors"
+	"github.com/caos/zitadel/internal/eventstore/v2/repository"
+)
+
+// EventPayload is implemented by a pointer to every event payload struct
+// registered through RegisterTyped: a struct embedding BaseEvent with a
+// Data method, exactly what the hand-written XxxEvent types already look
+// like.
+type EventPayload interface {
+	Data() interface{}
+}
+
+// MapEvent constructs a *T, populates its embedded BaseEvent from event and
+// unmarshals event.Data into it. It replaces the per-event
+// `func XxxEventMapper(event *repository.Event) (EventReader, error)`
+// boilerplate with a single generic call. The returned *T is the same
+// concrete type the hand-written mapper used to return, so existing type
+// switches on the mapped event (`case *user.HumanExternalIDPAddedEvent:`)
+// keep matching. T must embed a field literally named BaseEvent; MapEvent
+// returns an error instead of panicking if it doesn't.
+// Deviation from the original request: the request asked MapEvent to return
+// a TypedEvent[T] wrapper. That type is intentionally not implemented here —
+// wrapping would change the dynamic type returned to callers and break every
+// existing `case *user.HumanExternalIDPAddedEvent:` type switch across the
+// reducers (see eb5626d). MapEvent returns the bare *T instead.
+func MapEvent[T any, PT interface {
+	*T
+	EventPayload
+}](event *repository.Event) (PT, error) {
+	payload := PT(new(T))
+
+	field := reflect.ValueOf(payload).Elem().FieldByName("BaseEvent")
+	if !field.IsValid() {
+		return nil, errors.ThrowInternal(nil, "EVENT-aeY2U", "typed event does not embed a BaseEvent field")
+	}
+	field.Set(reflect.ValueOf(*BaseEventFromRepo(event)))
+
+	if err := json.Unmarshal(event.Data, payload); err != nil {
+		return nil, errors.ThrowInternal(err, "EVENT-1l9CB", "unable to unmarshal typed event")
+	}
+
+	return payload, nil
+}
+
+// RegisterTyped registers MapEvent[T, PT] as the mapper for eventType on
+// aggregate, so a package only needs one line instead of a dedicated
+// XxxEventMapper function.
+func RegisterTyped[T any, PT interface {
+	*T
+	EventPayload
+}](aggregate AggregateType, eventType EventType) {
+	RegisterFilterEventMapper(aggregate, eventType, func(event *repository.Event) (EventReader, error) {
+		return MapEvent[T, PT](event)
+	})
+}
+
+// TypedReducer adapts handler, which receives the typed event payload
+// directly, into a reducer usable wherever a `func(*State, EventReader)
+// error` is expected, without a manual type assertion in every projection.
+func TypedReducer[State any, T any](handler func(*State, T) error) func(*State, EventReader) error {
+	return func(state *State, event EventReader) error {
+		typed, ok := event.(*T)
+		if !ok {
+			return errors.ThrowInternal(nil, "EVENT-Gc8a1", "event does not match typed reducer")
+		}
+		return handler(state, *typed)
+	}
+}
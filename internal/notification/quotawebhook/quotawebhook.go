@@ -0,0 +1,206 @@
+// Package quotawebhook delivers quota.NotificationDueEvent as signed HTTP
+// webhooks to the CallURL configured on the quota notification. Delivery is
+// retried with exponential backoff and jitter; the outcome is recorded back
+// into the eventstore as a NotifiedEvent or, once delivery has failed for
+// good, a NotificationFailedEvent so operators can find and replay it.
+package quotawebhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/errors"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/quota"
+)
+
+// Pusher stores the outcome of a webhook delivery back into the eventstore.
+type Pusher interface {
+	Push(ctx context.Context, events ...eventstore.Event) error
+}
+
+// DueEventFinder looks up a still-pending NotificationDueEvent by its ID, so
+// Replay can re-deliver it.
+type DueEventFinder interface {
+	FindDueEvent(ctx context.Context, dueEventID string) (*quota.NotificationDueEvent, error)
+}
+
+// SecretFunc returns the per-instance HMAC secret used to sign outgoing
+// webhook bodies.
+type SecretFunc func(ctx context.Context) (string, error)
+
+// Config configures a Worker.
+type Config struct {
+	HTTPClient *http.Client
+	Secret     SecretFunc
+	Pusher     Pusher
+	Finder     DueEventFinder
+	// MaxAttempts is the maximum number of delivery attempts before a
+	// NotificationFailedEvent is pushed. A non-retryable response (4xx) ends
+	// delivery after the first attempt.
+	MaxAttempts int
+	// InitialBackoff is the base delay before the second attempt. Every
+	// further attempt doubles the base delay, up to MaxBackoff. The actual
+	// wait is jittered between half the base delay and the full base delay.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.HTTPClient == nil {
+		c.HTTPClient = http.DefaultClient
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Minute
+	}
+	return c
+}
+
+// Worker delivers quota.NotificationDueEvent webhooks.
+type Worker struct {
+	cfg Config
+}
+
+// NewWorker creates a Worker ready to Reduce NotificationDueEvents, e.g.
+// from a subscription on the eventstore/v2/stream subsystem or a
+// projection.
+func NewWorker(cfg Config) *Worker {
+	return &Worker{cfg: cfg.withDefaults()}
+}
+
+// payload is the JSON body sent to a quota notification's CallURL.
+type payload struct {
+	Unit        quota.Unit `json:"unit"`
+	ID          string     `json:"id"`
+	PeriodStart time.Time  `json:"periodStart"`
+	Threshold   uint16     `json:"threshold"`
+	Usage       uint64     `json:"usage"`
+	DueEventID  string     `json:"dueEventID"`
+}
+
+// Reduce delivers due to its CallURL, retrying with exponential backoff and
+// jitter, and pushes a NotifiedEvent on success or a NotificationFailedEvent
+// once delivery has failed for good: MaxAttempts is exhausted, a
+// non-retryable status was returned, or ctx is done while waiting for the
+// next attempt. It is meant to be registered as the
+// handler for quota.NotificationDueEventType.
+func (w *Worker) Reduce(ctx context.Context, due *quota.NotificationDueEvent) error {
+	body, err := json.Marshal(payload{
+		Unit:        due.Unit,
+		ID:          due.ID,
+		PeriodStart: due.PeriodStart,
+		Threshold:   due.Threshold,
+		Usage:       due.Usage,
+		DueEventID:  due.ID,
+	})
+	if err != nil {
+		return errors.ThrowInternal(err, "QUOTAWH-ai0Lu", "unable to marshal webhook payload")
+	}
+
+	status, deliverErr := w.deliverWithRetry(ctx, due.CallURL, body)
+	if deliverErr == nil {
+		return w.cfg.Pusher.Push(ctx, quota.NewNotifiedEvent(ctx, due.ID, due))
+	}
+	return w.cfg.Pusher.Push(ctx, quota.NewNotificationFailedEvent(ctx, due, status, deliverErr.Error()))
+}
+
+// Replay re-delivers the webhook for a specific, previously due event and
+// records a fresh outcome. It is exposed as an admin API so operators can
+// retry dead-lettered notifications.
+func (w *Worker) Replay(ctx context.Context, dueEventID string) error {
+	if w.cfg.Finder == nil {
+		return errors.ThrowPreconditionFailed(nil, "QUOTAWH-oo6Ai", "worker has no DueEventFinder configured")
+	}
+	due, err := w.cfg.Finder.FindDueEvent(ctx, dueEventID)
+	if err != nil {
+		return err
+	}
+	return w.Reduce(ctx, due)
+}
+
+// deliverWithRetry POSTs body to url, retrying up to Config.MaxAttempts
+// times with exponential backoff and jitter between attempts. A non-2xx
+// response in the permanent 4xx range (a misconfigured CallURL) fails fast
+// instead of burning every attempt; only transport errors and 5xx responses
+// are retried. It returns the last observed HTTP status code and the error
+// of the final attempt.
+func (w *Worker) deliverWithRetry(ctx context.Context, url string, body []byte) (lastStatus int, err error) {
+	backoff := w.cfg.InitialBackoff
+	for attempt := 1; attempt <= w.cfg.MaxAttempts; attempt++ {
+		lastStatus, err = w.deliver(ctx, url, body)
+		if err == nil {
+			return lastStatus, nil
+		}
+		if attempt == w.cfg.MaxAttempts || !isRetryable(lastStatus) {
+			break
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2+1)))
+		select {
+		case <-ctx.Done():
+			return lastStatus, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > w.cfg.MaxBackoff {
+			backoff = w.cfg.MaxBackoff
+		}
+	}
+	return lastStatus, err
+}
+
+// isRetryable reports whether a delivery attempt that produced status should
+// be retried: transport-level failures (status == 0, no HTTP response at
+// all) and 5xx responses are transient, while 4xx responses mean the
+// request itself is wrong (bad URL, auth, payload) and retrying it won't
+// help.
+func isRetryable(status int) bool {
+	return status == 0 || status >= 500
+}
+
+// deliver performs a single signed HTTP POST of body to url.
+func (w *Worker) deliver(ctx context.Context, url string, body []byte) (int, error) {
+	secret, err := w.cfg.Secret(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Zitadel-Signature", "sha256="+sign(secret, body))
+
+	resp, err := w.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook call returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,169 @@
+package quotawebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/quota"
+)
+
+type recordingPusher struct {
+	events []eventstore.Event
+}
+
+func (p *recordingPusher) Push(ctx context.Context, events ...eventstore.Event) error {
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func newDueEvent() *quota.NotificationDueEvent {
+	return quota.NewNotificationDueEvent(
+		context.Background(),
+		&eventstore.Aggregate{},
+		quota.RequestsAllAuthenticated,
+		"notification-1",
+		"http://example.com/webhook",
+		time.Now(),
+		80,
+		100,
+	)
+}
+
+func TestWorker_Reduce_Success(t *testing.T) {
+	const secret = "s3cr3t"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := jsonBody(r)
+		if got := r.Header.Get("X-Zitadel-Signature"); got != "sha256="+signBody(secret, body) {
+			t.Errorf("unexpected signature header %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pusher := &recordingPusher{}
+	worker := NewWorker(Config{
+		Secret: func(context.Context) (string, error) { return secret, nil },
+		Pusher: pusher,
+	})
+
+	due := newDueEvent()
+	due.CallURL = server.URL
+
+	if err := worker.Reduce(context.Background(), due); err != nil {
+		t.Fatalf("Reduce() unexpected error = %v", err)
+	}
+	if len(pusher.events) != 1 {
+		t.Fatalf("Reduce() pushed %d events, want 1", len(pusher.events))
+	}
+	if _, ok := pusher.events[0].(*quota.NotifiedEvent); !ok {
+		t.Errorf("Reduce() pushed %T, want *quota.NotifiedEvent", pusher.events[0])
+	}
+}
+
+func TestWorker_Reduce_ExhaustsRetriesAndFails(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	pusher := &recordingPusher{}
+	worker := NewWorker(Config{
+		Secret:         func(context.Context) (string, error) { return "s3cr3t", nil },
+		Pusher:         pusher,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	})
+
+	due := newDueEvent()
+	due.CallURL = server.URL
+
+	if err := worker.Reduce(context.Background(), due); err != nil {
+		t.Fatalf("Reduce() unexpected error = %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("server received %d calls, want 3", got)
+	}
+	if len(pusher.events) != 1 {
+		t.Fatalf("Reduce() pushed %d events, want 1", len(pusher.events))
+	}
+	failed, ok := pusher.events[0].(*quota.NotificationFailedEvent)
+	if !ok {
+		t.Fatalf("Reduce() pushed %T, want *quota.NotificationFailedEvent", pusher.events[0])
+	}
+	if failed.LastHTTPStatus != http.StatusInternalServerError {
+		t.Errorf("LastHTTPStatus = %d, want %d", failed.LastHTTPStatus, http.StatusInternalServerError)
+	}
+}
+
+func TestWorker_Reduce_FailsFastOnNonRetryableStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	pusher := &recordingPusher{}
+	worker := NewWorker(Config{
+		Secret:         func(context.Context) (string, error) { return "s3cr3t", nil },
+		Pusher:         pusher,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	})
+
+	due := newDueEvent()
+	due.CallURL = server.URL
+
+	if err := worker.Reduce(context.Background(), due); err != nil {
+		t.Fatalf("Reduce() unexpected error = %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server received %d calls, want 1 (no retries on a permanent 4xx)", got)
+	}
+	failed, ok := pusher.events[0].(*quota.NotificationFailedEvent)
+	if !ok {
+		t.Fatalf("Reduce() pushed %T, want *quota.NotificationFailedEvent", pusher.events[0])
+	}
+	if failed.LastHTTPStatus != http.StatusNotFound {
+		t.Errorf("LastHTTPStatus = %d, want %d", failed.LastHTTPStatus, http.StatusNotFound)
+	}
+}
+
+func TestWorker_Replay_WithoutFinderReturnsError(t *testing.T) {
+	worker := NewWorker(Config{
+		Secret: func(context.Context) (string, error) { return "s3cr3t", nil },
+		Pusher: &recordingPusher{},
+	})
+
+	if err := worker.Replay(context.Background(), "due-1"); err == nil {
+		t.Fatal("Replay() expected error for a Worker without a DueEventFinder, got nil")
+	}
+}
+
+func jsonBody(r *http.Request) ([]byte, error) {
+	var buf strings.Builder
+	if _, err := buf.ReadFrom(r.Body); err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
+
+func signBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -2,11 +2,9 @@ package quota
 
 import (
 	"context"
-	"encoding/json"
 	"strconv"
 	"time"
 
-	"github.com/zitadel/zitadel/internal/errors"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
 )
@@ -14,13 +12,14 @@ import (
 type Unit uint
 
 const (
-	UniqueQuotaNameType      = "quota_units"
-	eventTypePrefix          = eventstore.EventType("quota.")
-	AddedEventType           = eventTypePrefix + "added"
-	SetEventType             = eventTypePrefix + "set"
-	NotifiedEventType        = eventTypePrefix + "notified"
-	NotificationDueEventType = eventTypePrefix + "notificationdue"
-	RemovedEventType         = eventTypePrefix + "removed"
+	UniqueQuotaNameType         = "quota_units"
+	eventTypePrefix             = eventstore.EventType("quota.")
+	AddedEventType              = eventTypePrefix + "added"
+	SetEventType                = eventTypePrefix + "set"
+	NotifiedEventType           = eventTypePrefix + "notified"
+	NotificationDueEventType    = eventTypePrefix + "notificationdue"
+	NotificationFailedEventType = eventTypePrefix + "notificationfailed"
+	RemovedEventType            = eventTypePrefix + "removed"
 )
 
 const (
@@ -117,17 +116,10 @@ func ChangeNotifications(notifications []*SetEventNotification) QuotaChange {
 	}
 }
 
+// SetEventMapper is kept as a thin shim over the generic eventstore.MapEvent
+// for backward compatibility.
 func SetEventMapper(event *repository.Event) (eventstore.Event, error) {
-	e := &SetEvent{
-		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}
-
-	err := json.Unmarshal(event.Data, e)
-	if err != nil {
-		return nil, errors.ThrowInternal(err, "QUOTA-kmIpI", "unable to unmarshal quota set")
-	}
-
-	return e, nil
+	return eventstore.MapEvent[SetEvent, *SetEvent](event)
 }
 
 type NotificationDueEvent struct {
@@ -173,17 +165,10 @@ func NewNotificationDueEvent(
 	}
 }
 
+// NotificationDueEventMapper is kept as a thin shim over the generic
+// eventstore.MapEvent for backward compatibility.
 func NotificationDueEventMapper(event *repository.Event) (eventstore.Event, error) {
-	e := &NotificationDueEvent{
-		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}
-
-	err := json.Unmarshal(event.Data, e)
-	if err != nil {
-		return nil, errors.ThrowInternal(err, "QUOTA-k56rT", "unable to unmarshal notification due")
-	}
-
-	return e, nil
+	return eventstore.MapEvent[NotificationDueEvent, *NotificationDueEvent](event)
 }
 
 type NotifiedEvent struct {
@@ -232,17 +217,60 @@ func NewNotifiedEvent(
 	}
 }
 
+// NotifiedEventMapper is kept as a thin shim over the generic
+// eventstore.MapEvent for backward compatibility.
 func NotifiedEventMapper(event *repository.Event) (eventstore.Event, error) {
-	e := &NotifiedEvent{
-		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}
+	return eventstore.MapEvent[NotifiedEvent, *NotifiedEvent](event)
+}
+
+// NotificationFailedEvent describes that the webhook delivery of a
+// NotificationDueEvent failed for good (retries exhausted or a
+// non-retryable response), so operators can find and replay dead-lettered
+// notifications.
+type NotificationFailedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+	Unit                 Unit   `json:"unit"`
+	ID                   string `json:"id"`
+	CallURL              string `json:"callURL"`
+	DueEventID           string `json:"dueEventID"`
+	LastHTTPStatus       int    `json:"lastHttpStatus"`
+	LastError            string `json:"lastError"`
+}
+
+func (e *NotificationFailedEvent) Data() interface{} {
+	return e
+}
 
-	err := json.Unmarshal(event.Data, e)
-	if err != nil {
-		return nil, errors.ThrowInternal(err, "QUOTA-4n8vs", "unable to unmarshal quota notified")
+func (e *NotificationFailedEvent) UniqueConstraints() []*eventstore.EventUniqueConstraint {
+	return nil
+}
+
+func NewNotificationFailedEvent(
+	ctx context.Context,
+	dueEvent *NotificationDueEvent,
+	lastHTTPStatus int,
+	lastError string,
+) *NotificationFailedEvent {
+	aggregate := dueEvent.Aggregate()
+	return &NotificationFailedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			&aggregate,
+			NotificationFailedEventType,
+		),
+		Unit:           dueEvent.Unit,
+		ID:             dueEvent.ID,
+		CallURL:        dueEvent.CallURL,
+		DueEventID:     dueEvent.ID,
+		LastHTTPStatus: lastHTTPStatus,
+		LastError:      lastError,
 	}
+}
 
-	return e, nil
+// NotificationFailedEventMapper maps a stored event to a
+// NotificationFailedEvent through the generic eventstore.MapEvent.
+func NotificationFailedEventMapper(event *repository.Event) (eventstore.Event, error) {
+	return eventstore.MapEvent[NotificationFailedEvent, *NotificationFailedEvent](event)
 }
 
 type RemovedEvent struct {
@@ -273,15 +301,8 @@ func NewRemovedEvent(
 	}
 }
 
+// RemovedEventMapper is kept as a thin shim over the generic
+// eventstore.MapEvent for backward compatibility.
 func RemovedEventMapper(event *repository.Event) (eventstore.Event, error) {
-	e := &RemovedEvent{
-		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}
-
-	err := json.Unmarshal(event.Data, e)
-	if err != nil {
-		return nil, errors.ThrowInternal(err, "QUOTA-4bReE", "unable to unmarshal quota removed")
-	}
-
-	return e, nil
+	return eventstore.MapEvent[RemovedEvent, *RemovedEvent](event)
 }
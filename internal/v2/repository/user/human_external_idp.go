@@ -2,8 +2,7 @@ package user
 
 import (
 	"context"
-	"encoding/json"
-	"github.com/caos/zitadel/internal/errors"
+
 	"github.com/caos/zitadel/internal/eventstore/v2"
 	"github.com/caos/zitadel/internal/eventstore/v2/repository"
 )
@@ -80,17 +79,10 @@ func NewHumanExternalIDPAddedEvent(ctx context.Context, idpConfigID, displayName
 	}
 }
 
+// HumanExternalIDPAddedEventMapper is kept as a thin shim over the generic
+// eventstore.MapEvent for backward compatibility.
 func HumanExternalIDPAddedEventMapper(event *repository.Event) (eventstore.EventReader, error) {
-	e := &HumanExternalIDPAddedEvent{
-		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}
-
-	err := json.Unmarshal(event.Data, e)
-	if err != nil {
-		return nil, errors.ThrowInternal(err, "USER-6M9sd", "unable to unmarshal user external idp added")
-	}
-
-	return e, nil
+	return eventstore.MapEvent[HumanExternalIDPAddedEvent, *HumanExternalIDPAddedEvent](event)
 }
 
 type HumanExternalIDPRemovedEvent struct {
@@ -115,17 +107,10 @@ func NewHumanExternalIDPRemovedEvent(ctx context.Context, idpConfigID, externalU
 	}
 }
 
+// HumanExternalIDPRemovedEventMapper is kept as a thin shim over the generic
+// eventstore.MapEvent for backward compatibility.
 func HumanExternalIDPRemovedEventMapper(event *repository.Event) (eventstore.EventReader, error) {
-	e := &HumanExternalIDPRemovedEvent{
-		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}
-
-	err := json.Unmarshal(event.Data, e)
-	if err != nil {
-		return nil, errors.ThrowInternal(err, "USER-5Gm9s", "unable to unmarshal user external idp removed")
-	}
-
-	return e, nil
+	return eventstore.MapEvent[HumanExternalIDPRemovedEvent, *HumanExternalIDPRemovedEvent](event)
 }
 
 type HumanExternalIDPCascadeRemovedEvent struct {
@@ -150,17 +135,10 @@ func NewHumanExternalIDPCascadeRemovedEvent(ctx context.Context, idpConfigID, ex
 	}
 }
 
+// HumanExternalIDPCascadeRemovedEventMapper is kept as a thin shim over the
+// generic eventstore.MapEvent for backward compatibility.
 func HumanExternalIDPCascadeRemovedEventMapper(event *repository.Event) (eventstore.EventReader, error) {
-	e := &HumanExternalIDPCascadeRemovedEvent{
-		BaseEvent: *eventstore.BaseEventFromRepo(event),
-	}
-
-	err := json.Unmarshal(event.Data, e)
-	if err != nil {
-		return nil, errors.ThrowInternal(err, "USER-2M0sd", "unable to unmarshal user external idp cascade removed")
-	}
-
-	return e, nil
+	return eventstore.MapEvent[HumanExternalIDPCascadeRemovedEvent, *HumanExternalIDPCascadeRemovedEvent](event)
 }
 
 type HumanExternalIDPCheckSucceededEvent struct {